@@ -4,13 +4,22 @@ package display
 import (
 	"bytes"
 	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
 )
 
 const XRES = 500
 const YRES = 500
 const PPMFilename = "pic.ppm"
+const PNGFilename = "pic.png"
 
 // NewScreen creates a new screen of size XRES by YRES. It returns the new
 // screen.
@@ -28,13 +37,32 @@ func NewScreen() (screen [][][]int) {
 	return
 }
 
-// DisplayScreen uses XQuartz's "display" command to display a PPM.
+// DisplayScreen opens a screen in an image viewer. It writes the screen to a
+// temporary PNG and opens it with "open" on macOS or "xdg-open" on Linux,
+// falling back to XQuartz's "display" if neither is found.
 func DisplayScreen(screen [][][]int) {
-	WriteScreenToPPM(screen)
-	_, err := exec.Command("display", PPMFilename).Output()
-	if err != nil {
-		panic(err)
+	WritePNG(screen, PNGFilename)
+
+	opener := "xdg-open"
+	if runtime.GOOS == "darwin" {
+		opener = "open"
+	}
+
+	if _, err := exec.LookPath(opener); err == nil {
+		if err := exec.Command(opener, PNGFilename).Run(); err != nil {
+			panic(err)
+		}
+		return
 	}
+
+	if _, err := exec.LookPath("display"); err == nil {
+		if err := exec.Command("display", PNGFilename).Run(); err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	panic(fmt.Sprintf("display: no image viewer found (tried %q and display)", opener))
 }
 
 // ClearScreen clears a screen.
@@ -48,13 +76,80 @@ func ClearScreen(screen [][][]int) {
 	}
 }
 
-// WriteScreenToExtension writes a screen to a filename.
+// screenToRGBA converts a screen to an *image.RGBA of the same dimensions.
+func screenToRGBA(screen [][][]int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, XRES, YRES))
+
+	for y := 0; y < YRES; y++ {
+		for x := 0; x < XRES; x++ {
+			rgb := screen[y][x]
+			img.SetRGBA(x, y, color.RGBA{
+				R: uint8(rgb[0]),
+				G: uint8(rgb[1]),
+				B: uint8(rgb[2]),
+				A: 255,
+			})
+		}
+	}
+
+	return img
+}
+
+// WriteScreenToExtension writes a screen to filename, encoding it according
+// to filename's extension (.png, .jpg/.jpeg, .gif, or .ppm).
 func WriteScreenToExtension(screen [][][]int, filename string) {
-	WriteScreenToPPM(screen)
-	_, err := exec.Command("convert", PPMFilename, filename).Output()
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".png":
+		WritePNG(screen, filename)
+	case ".jpg", ".jpeg":
+		WriteJPEG(screen, filename, 90)
+	case ".gif":
+		WriteGIF(screen, filename)
+	case ".ppm":
+		WriteScreenToPPM(screen)
+	default:
+		panic(fmt.Sprintf("display: unsupported file extension %q", filepath.Ext(filename)))
+	}
+}
+
+// WritePNG encodes a screen as a PNG and writes it to filename.
+func WritePNG(screen [][][]int, filename string) {
+	file, err := os.Create(filename)
 	if err != nil {
 		panic(err)
 	}
+	defer file.Close()
+
+	if err := png.Encode(file, screenToRGBA(screen)); err != nil {
+		panic(err)
+	}
+}
+
+// WriteJPEG encodes a screen as a JPEG at the given quality (1-100) and
+// writes it to filename.
+func WriteJPEG(screen [][][]int, filename string, quality int) {
+	file, err := os.Create(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	if err := jpeg.Encode(file, screenToRGBA(screen), &jpeg.Options{Quality: quality}); err != nil {
+		panic(err)
+	}
+}
+
+// WriteGIF encodes a screen as a GIF and writes it to filename.
+func WriteGIF(screen [][][]int, filename string) {
+	file, err := os.Create(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	if err := gif.Encode(file, screenToRGBA(screen), nil); err != nil {
+		panic(err)
+	}
 }
 
 // WriteScreenToPPM takes a screen as an argument and writes it to a PPM file.
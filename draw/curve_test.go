@@ -0,0 +1,31 @@
+package draw
+
+import "testing"
+
+// TestAddCurveAdaptiveSegmentCounts compares the number of segments
+// AddCurveAdaptive emits against AddCurve's fixed 0.01 t-step: a nearly
+// straight curve should collapse to far fewer segments, while a sharply
+// curved one still needs several to stay within tolerance.
+func TestAddCurveAdaptiveSegmentCounts(t *testing.T) {
+	straightFixed := make([][]float64, 4)
+	straightAdaptive := make([][]float64, 4)
+	AddCurve(straightFixed, 0, 0, 33, 0, 66, 0, 100, 0, 0.01, "bezier")
+	AddCurveAdaptive(straightAdaptive, 0, 0, 33, 0, 66, 0, 100, 0, DefaultCurveTolerance, "bezier")
+
+	fixedSegments := len(straightFixed[0]) / 2
+	straightAdaptiveSegments := len(straightAdaptive[0]) / 2
+
+	if straightAdaptiveSegments >= fixedSegments {
+		t.Errorf("straight curve: adaptive segments (%d) should be far fewer than fixed-step segments (%d)",
+			straightAdaptiveSegments, fixedSegments)
+	}
+
+	curvyAdaptive := make([][]float64, 4)
+	AddCurveAdaptive(curvyAdaptive, 0, 0, 100, 100, 0, 100, 100, 0, DefaultCurveTolerance, "bezier")
+	curvyAdaptiveSegments := len(curvyAdaptive[0]) / 2
+
+	if curvyAdaptiveSegments <= straightAdaptiveSegments {
+		t.Errorf("curvy curve should need more adaptive segments (%d) than the straight one (%d)",
+			curvyAdaptiveSegments, straightAdaptiveSegments)
+	}
+}
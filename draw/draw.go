@@ -4,13 +4,49 @@ package draw
 
 import (
 	"github.com/jkao1/yet-another-3d-thing/display"
+	"github.com/jkao1/yet-another-3d-thing/draw/raster"
 	"github.com/jkao1/yet-another-3d-thing/matrix"
 
 	"math"
+	"sort"
 )
 
 var DefaultDrawColor []int = []int{0, 0, 0}
 
+// antialiasEnabled controls whether DrawLines rasterizes edges with the
+// antialiased raster package instead of the aliased Bresenham DrawLine.
+var antialiasEnabled = false
+
+// SetAntialias turns antialiased line drawing on or off. It's off by default
+// so existing wireframes stay bit-exact.
+func SetAntialias(enabled bool) {
+	antialiasEnabled = enabled
+}
+
+// meshEnabled controls whether sphere/box/torus commands add solid polygons
+// (for DrawPolygons) instead of the disconnected unit-length wireframe edges
+// AddSphere/AddBox/AddTorus produce.
+var meshEnabled = false
+
+// SetMesh turns solid polygon-mesh rendering on or off. It's off by default
+// so existing scripts keep rendering wireframes.
+func SetMesh(enabled bool) {
+	meshEnabled = enabled
+}
+
+// Mesh reports whether solid polygon-mesh rendering is currently enabled.
+func Mesh() bool {
+	return meshEnabled
+}
+
+// DefaultCurveTolerance is the default flatness tolerance, in pixels, used by
+// AddCurveAdaptive when the caller doesn't supply one.
+const DefaultCurveTolerance = 0.5
+
+// maxCurveRecursionDepth bounds the de Casteljau subdivision recursion so a
+// degenerate curve can't recurse forever.
+const maxCurveRecursionDepth = 32
+
 // DrawLines draws an edge matrix onto a screen.
 func DrawLines(edges [][]float64, screen [][][]int) {
 	for i := 0; i < len(edges[0])-1; i += 2 {
@@ -18,7 +54,11 @@ func DrawLines(edges [][]float64, screen [][][]int) {
 		nextPoint := matrix.ExtractColumn(edges, i+1)
 		x0, y0 := point[0], point[1]
 		x1, y1 := nextPoint[0], nextPoint[1]
-		DrawLine(screen, x0, y0, x1, y1)
+		if antialiasEnabled {
+			raster.DrawLine(screen, x0, y0, x1, y1, DefaultDrawColor)
+		} else {
+			DrawLine(screen, x0, y0, x1, y1)
+		}
 	}
 }
 
@@ -30,6 +70,124 @@ func AddPoint(m [][]float64, x, y, z float64) {
 	m[3] = append(m[3], 1)
 }
 
+// AddPolygon adds a triangular face (three points) to a polygon matrix, and
+// records color as that triangle's entry in the parallel colors list
+// DrawPolygons reads at draw time.
+func AddPolygon(m [][]float64, colors *[][]int, color []int, params ...float64) {
+	x0, y0, z0 := params[0], params[1], params[2]
+	x1, y1, z1 := params[3], params[4], params[5]
+	x2, y2, z2 := params[6], params[7], params[8]
+	AddPoint(m, x0, y0, z0)
+	AddPoint(m, x1, y1, z1)
+	AddPoint(m, x2, y2, z2)
+	*colors = append(*colors, color)
+}
+
+// addTriangle adds the triangular face p0-p1-p2, in color, to a polygon
+// matrix and its parallel colors list. p0, p1 and p2 are (x, y, z) points, as
+// returned by matrix.ExtractColumn or the Generate* grid helpers.
+func addTriangle(m [][]float64, colors *[][]int, color []int, p0, p1, p2 []float64) {
+	AddPolygon(m, colors, color, p0[0], p0[1], p0[2], p1[0], p1[1], p1[2], p2[0], p2[1], p2[2])
+}
+
+// DrawPolygons scanline-fills every front-facing triangle in a polygon
+// matrix onto a screen, using colors[i] as the i-th triangle's color (falling
+// back to DefaultDrawColor for any triangle colors doesn't cover). Each
+// triangle is filled via a one-off GraphicContext, since GraphicContext.Fill
+// only carries a single gc.Color and triangles can each have their own.
+func DrawPolygons(polygons [][]float64, colors [][]int, screen [][][]int) {
+	for i := 0; i < len(polygons[0])-2; i += 3 {
+		p0 := matrix.ExtractColumn(polygons, i)
+		p1 := matrix.ExtractColumn(polygons, i+1)
+		p2 := matrix.ExtractColumn(polygons, i+2)
+
+		if !isFrontFacing(p0, p1, p2) {
+			continue
+		}
+
+		color := DefaultDrawColor
+		if triangle := i / 3; triangle < len(colors) {
+			color = colors[triangle]
+		}
+
+		gc := NewGraphicContext(screen)
+		gc.Color = color
+		gc.MoveTo(p0[0], p0[1])
+		gc.LineTo(p1[0], p1[1])
+		gc.LineTo(p2[0], p2[1])
+		gc.Fill()
+	}
+}
+
+// isFrontFacing reports whether triangle p0-p1-p2 faces the camera, based on
+// the sign of the Z component of its surface normal.
+func isFrontFacing(p0, p1, p2 []float64) bool {
+	normal := matrix.CrossProduct(matrix.Subtract(p1, p0), matrix.Subtract(p2, p0))
+	return normal[2] > 0
+}
+
+// polyEdge is one edge of a polygon, bucketed for the active-edge-table scan
+// in fillPolygon.
+type polyEdge struct {
+	yTop, yBottom float64
+	xAtYTop       float64
+	invSlope      float64 // dx/dy
+}
+
+// fillPolygonColor fills the polygon defined by verts (in order, wrapping
+// back to the first vertex) onto screen with color. It builds an
+// active-edge-table: every non-horizontal edge is bucketed by its starting
+// scanline, then at each scanline the edges spanning it are paired up by X
+// to fill the spans between them.
+func fillPolygonColor(screen [][][]int, verts [][]float64, color []int) {
+	edges := make([]polyEdge, 0, len(verts))
+	for i, a := range verts {
+		b := verts[(i+1)%len(verts)]
+		if a[1] == b[1] {
+			continue // horizontal edges don't contribute X intersections
+		}
+		if a[1] > b[1] {
+			a, b = b, a
+		}
+		edges = append(edges, polyEdge{
+			yTop:     a[1],
+			yBottom:  b[1],
+			xAtYTop:  a[0],
+			invSlope: (b[0] - a[0]) / (b[1] - a[1]),
+		})
+	}
+
+	if len(edges) == 0 {
+		return
+	}
+
+	minY, maxY := edges[0].yTop, edges[0].yBottom
+	for _, e := range edges {
+		if e.yTop < minY {
+			minY = e.yTop
+		}
+		if e.yBottom > maxY {
+			maxY = e.yBottom
+		}
+	}
+
+	for y := math.Ceil(minY); y <= maxY; y++ {
+		xs := make([]float64, 0, len(edges))
+		for _, e := range edges {
+			if y >= e.yTop && y < e.yBottom {
+				xs = append(xs, e.xAtYTop+(y-e.yTop)*e.invSlope)
+			}
+		}
+		sort.Float64s(xs)
+
+		for i := 0; i+1 < len(xs); i += 2 {
+			for x := math.Ceil(xs[i]); x <= xs[i+1]; x++ {
+				plotColor(screen, x, y, color)
+			}
+		}
+	}
+}
+
 // AddEdge adds an edge (two points) to an edge matrix.
 func AddEdge(m [][]float64, params ...float64) {
 	x0, y0, z0 := params[0], params[1], params[2]
@@ -63,6 +221,71 @@ func AddCurve(m [][]float64, x0, y0, x1, y1, x2, y2, x3, y3, step float64, curve
 	}
 }
 
+// AddCurveAdaptive adds the curve bounded by the 4 points passed as parameters
+// to an edge matrix, using recursive de Casteljau subdivision instead of a
+// fixed t-step. For curveType "hermite", (x1, y1) is the curve's other
+// endpoint and (x2, y2)/(x3, y3) are the tangents at P0/P1; these are first
+// converted to the equivalent Bezier control points. Subdivision stops once
+// the curve is flat enough that the perpendicular distance from both
+// interior control points to the P0-P3 chord is within tolerance, or once
+// maxCurveRecursionDepth is reached.
+func AddCurveAdaptive(m [][]float64, x0, y0, x1, y1, x2, y2, x3, y3, tolerance float64, curveType string) {
+	if curveType == "hermite" {
+		p0x, p0y := x0, y0
+		p3x, p3y := x1, y1
+		p1x, p1y := p0x+x2/3, p0y+y2/3
+		p2x, p2y := p3x-x3/3, p3y-y3/3
+		x0, y0, x1, y1, x2, y2, x3, y3 = p0x, p0y, p1x, p1y, p2x, p2y, p3x, p3y
+	}
+
+	subdivideBezier(m, x0, y0, x1, y1, x2, y2, x3, y3, tolerance, 0)
+}
+
+// subdivideBezier recursively splits the cubic Bezier curve (x0,y0)-(x3,y3)
+// at t=0.5 via de Casteljau's algorithm, emitting the chord of each flat-
+// enough leaf as a segment in the edge matrix.
+func subdivideBezier(m [][]float64, x0, y0, x1, y1, x2, y2, x3, y3, tolerance float64, depth int) {
+	if depth >= maxCurveRecursionDepth || isFlat(x0, y0, x1, y1, x2, y2, x3, y3, tolerance) {
+		AddPoint(m, x0, y0, 0)
+		AddPoint(m, x3, y3, 0)
+		return
+	}
+
+	x01, y01 := midpoint(x0, y0, x1, y1)
+	x12, y12 := midpoint(x1, y1, x2, y2)
+	x23, y23 := midpoint(x2, y2, x3, y3)
+	x012, y012 := midpoint(x01, y01, x12, y12)
+	x123, y123 := midpoint(x12, y12, x23, y23)
+	x0123, y0123 := midpoint(x012, y012, x123, y123)
+
+	subdivideBezier(m, x0, y0, x01, y01, x012, y012, x0123, y0123, tolerance, depth+1)
+	subdivideBezier(m, x0123, y0123, x123, y123, x23, y23, x3, y3, tolerance, depth+1)
+}
+
+// isFlat reports whether the cubic Bezier curve defined by (x0,y0)..(x3,y3)
+// is flat enough to draw as the single chord P0->P3, i.e. both interior
+// control points lie within tolerance pixels of that chord.
+func isFlat(x0, y0, x1, y1, x2, y2, x3, y3, tolerance float64) bool {
+	return perpDistance(x1, y1, x0, y0, x3, y3) <= tolerance &&
+		perpDistance(x2, y2, x0, y0, x3, y3) <= tolerance
+}
+
+// perpDistance returns the perpendicular distance from (px, py) to the line
+// through (x0, y0) and (x1, y1).
+func perpDistance(px, py, x0, y0, x1, y1 float64) float64 {
+	dx, dy := x1-x0, y1-y0
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return math.Hypot(px-x0, py-y0)
+	}
+	return math.Abs((px-x0)*dy-(py-y0)*dx) / length
+}
+
+// midpoint returns the point halfway between (x0, y0) and (x1, y1).
+func midpoint(x0, y0, x1, y1 float64) (float64, float64) {
+	return (x0 + x1) / 2, (y0 + y1) / 2
+}
+
 func generateCurveCoefs(p0, p1, p2, p3 float64, curveType string) [][]float64 {
 	m := make([][]float64, 4)
 	var coefGenerator [][]float64
@@ -100,6 +323,40 @@ func AddBox(m [][]float64, a ...float64) {
 	AddEdge(m, x+width, y, z-depth, x+width, y-height, z-depth)
 }
 
+// AddBoxPolygons adds the 12 triangles (2 per face) for a rectangular prism
+// whose upper-left corner is (x, y, z) with width, height and depth
+// dimensions, in color, to a polygon matrix.
+func AddBoxPolygons(m [][]float64, colors *[][]int, color []int, a ...float64) {
+	x, y, z, width, height, depth := a[0], a[1], a[2], a[3], a[4], a[5]
+
+	ftl := []float64{x, y, z}
+	ftr := []float64{x + width, y, z}
+	fbl := []float64{x, y - height, z}
+	fbr := []float64{x + width, y - height, z}
+	btl := []float64{x, y, z - depth}
+	btr := []float64{x + width, y, z - depth}
+	bbl := []float64{x, y - height, z - depth}
+	bbr := []float64{x + width, y - height, z - depth}
+
+	// front, back
+	addTriangle(m, colors, color, ftl, fbr, ftr)
+	addTriangle(m, colors, color, ftl, fbl, fbr)
+	addTriangle(m, colors, color, btr, bbl, btl)
+	addTriangle(m, colors, color, btr, bbr, bbl)
+
+	// left, right
+	addTriangle(m, colors, color, btl, fbl, ftl)
+	addTriangle(m, colors, color, btl, bbl, fbl)
+	addTriangle(m, colors, color, ftr, bbr, btr)
+	addTriangle(m, colors, color, ftr, fbr, bbr)
+
+	// top, bottom
+	addTriangle(m, colors, color, btl, ftr, btr)
+	addTriangle(m, colors, color, btl, ftl, ftr)
+	addTriangle(m, colors, color, fbl, bbr, fbr)
+	addTriangle(m, colors, color, fbl, bbl, bbr)
+}
+
 // AddSphere adds all the points for a sphere with center (cx, cy, cz) and
 // radius r.
 func AddSphere(m [][]float64, a ...float64) {
@@ -126,6 +383,37 @@ func GenerateSphere(cx, cy, cz, r float64) [][]float64 {
 	return points
 }
 
+// sphereLongSteps and sphereLatSteps are the grid resolution used by
+// GenerateSphereGrid/AddSpherePolygons, chosen to match GenerateSphere's
+// 0.01 step over the same ranges.
+const sphereLongSteps = 100
+const sphereLatSteps = 50
+
+// AddSpherePolygons adds the triangles covering the surface of a sphere with
+// center (cx, cy, cz) and radius r, in color, to a polygon matrix.
+func AddSpherePolygons(m [][]float64, colors *[][]int, color []int, a ...float64) {
+	cx, cy, cz, r := a[0], a[1], a[2], a[3]
+	addGridPolygons(m, colors, color, GenerateSphereGrid(cx, cy, cz, r), sphereLongSteps, sphereLatSteps, false)
+}
+
+// GenerateSphereGrid generates the points along the surface of a sphere with
+// center (cx, cy, cz) and radius r, laid out as a (sphereLongSteps+1) by
+// (sphereLatSteps+1) grid in row-major order, for use by AddSpherePolygons.
+func GenerateSphereGrid(cx, cy, cz, r float64) [][]float64 {
+	points := make([][]float64, 0, (sphereLongSteps+1)*(sphereLatSteps+1))
+	for i := 0; i <= sphereLongSteps; i++ {
+		fi := 2 * math.Pi * float64(i) / sphereLongSteps
+		for j := 0; j <= sphereLatSteps; j++ {
+			theta := math.Pi * float64(j) / sphereLatSteps
+			x := r*math.Cos(theta) + cx
+			y := r*math.Sin(theta)*math.Cos(fi) + cy
+			z := r*math.Sin(theta)*math.Sin(fi) + cz
+			points = append(points, []float64{x, y, z})
+		}
+	}
+	return points
+}
+
 // AddTorus adds all the points required to make a torus with center
 // (cx, cy, cz) and radii r1 and r2.
 func AddTorus(m [][]float64, a ...float64) {
@@ -135,6 +423,64 @@ func AddTorus(m [][]float64, a ...float64) {
 	}
 }
 
+// torusLongSteps and torusLatSteps are the grid resolution used by
+// GenerateTorusGrid/AddTorusPolygons, chosen to match GenerateTorus's 0.01
+// step over the same ranges.
+const torusLongSteps = 100
+const torusLatSteps = 100
+
+// AddTorusPolygons adds the triangles covering the surface of a torus with
+// center (cx, cy, cz) and radii r1 and r2, in color, to a polygon matrix.
+func AddTorusPolygons(m [][]float64, colors *[][]int, color []int, a ...float64) {
+	cx, cy, cz, r1, r2 := a[0], a[1], a[2], a[3], a[4]
+	addGridPolygons(m, colors, color, GenerateTorusGrid(cx, cy, cz, r1, r2), torusLongSteps, torusLatSteps, true)
+}
+
+// GenerateTorusGrid generates the points along the surface of a torus with
+// center (cx, cy, cz) and radii r1 and r2, laid out as a
+// (torusLongSteps+1) by (torusLatSteps+1) grid in row-major order, for use
+// by AddTorusPolygons.
+func GenerateTorusGrid(cx, cy, cz, r2, r1 float64) [][]float64 {
+	points := make([][]float64, 0, (torusLongSteps+1)*(torusLatSteps+1))
+	for i := 0; i <= torusLongSteps; i++ {
+		fi := 2 * math.Pi * float64(i) / torusLongSteps
+		for j := 0; j <= torusLatSteps; j++ {
+			theta := 2 * math.Pi * float64(j) / torusLatSteps
+			x := math.Cos(fi)*(r2*math.Cos(theta)+r1) + cx
+			y := r2*math.Sin(theta) + cy
+			z := -1*math.Sin(fi)*(r2*math.Cos(theta)+r1) + cz
+			points = append(points, []float64{x, y, z})
+		}
+	}
+	return points
+}
+
+// addGridPolygons connects a (longSteps+1) by (latSteps+1) row-major point
+// grid, as generated by GenerateSphereGrid/GenerateTorusGrid, into triangles
+// in color, and adds them to a polygon matrix. flipWinding reverses the
+// triangles' vertex order: GenerateTorusGrid's extra sign flip (needed to
+// match GenerateTorus's wireframe orientation) inverts the grid's handedness
+// relative to GenerateSphereGrid, so the torus caller passes true to keep its
+// triangles' normals pointing outward like the sphere's.
+func addGridPolygons(m [][]float64, colors *[][]int, color []int, points [][]float64, longSteps, latSteps int, flipWinding bool) {
+	numLat := latSteps + 1
+	for i := 0; i < longSteps; i++ {
+		for j := 0; j < latSteps; j++ {
+			p0 := points[i*numLat+j]
+			p1 := points[i*numLat+j+1]
+			p2 := points[(i+1)*numLat+j]
+			p3 := points[(i+1)*numLat+j+1]
+			if flipWinding {
+				addTriangle(m, colors, color, p0, p2, p1)
+				addTriangle(m, colors, color, p1, p2, p3)
+			} else {
+				addTriangle(m, colors, color, p0, p1, p2)
+				addTriangle(m, colors, color, p1, p3, p2)
+			}
+		}
+	}
+}
+
 // GenerateTorus  generates all the points along the surface of a torus with
 // center (cx, cy, cz) and radii r1 and r2.
 func GenerateTorus(cx, cy, cz, r2, r1 float64) [][]float64 {
@@ -160,83 +506,112 @@ func CubicEval(x float64, coefs [][]float64) (y float64) {
 	return
 }
 
-// DrawLine draws a line from (x0, y0) to (x1, y1) onto a screen.
+// fixShift is the number of fractional bits in the 26.6 fixed-point
+// coordinates drawLineFixed rasterizes with.
+const fixShift = 6
+const fixOne = 1 << fixShift
+
+// Fix converts a float64 pixel coordinate to 26.6 fixed-point.
+func Fix(x float64) int32 {
+	return int32(x * fixOne)
+}
+
+// DrawLine draws a line from (x0, y0) to (x1, y1) onto a screen with the
+// default draw color, by delegating to a one-off default GraphicContext.
 func DrawLine(screen [][][]int, x0, y0, x1, y1 float64) {
+	gc := NewGraphicContext(screen)
+	gc.MoveTo(x0, y0)
+	gc.LineTo(x1, y1)
+	gc.Stroke()
+}
+
+// rasterizeLine converts (x0, y0)-(x1, y1) to fixed-point and draws them with
+// color via drawLineFixed. It's GraphicContext.drawSegment's core rasterizer;
+// DrawLine reaches it indirectly through a default GraphicContext's Stroke.
+func rasterizeLine(screen [][][]int, x0, y0, x1, y1 float64, color []int) {
+	drawLineFixed(screen, Fix(x0), Fix(y0), Fix(x1), Fix(y1), color)
+}
+
+// drawLineFixed is aliased Bresenham over 26.6 fixed-point coordinates.
+// Endpoints are always sorted by X (and by Y for vertical lines) before
+// rasterizing, and all slope/step arithmetic runs in int64, so
+// DrawLine(a, b) and DrawLine(b, a) always rasterize to the same pixels.
+func drawLineFixed(screen [][][]int, x0, y0, x1, y1 int32, color []int) {
 	if x1 < x0 {
 		x0, x1 = x1, x0
 		y0, y1 = y1, y0
 	}
 
-	A := y1 - y0
-	B := x0 - x1
-	x := x0
-	y := y0
+	x, y := int64(x0), int64(y0)
+	endX, endY := int64(x1), int64(y1)
+	A := endY - y
+	B := x - endX
+	const step = int64(fixOne)
 
 	if B == 0 { // vertical line
-		if y1 < y0 {
-			y0, y1 = y1, y0
+		if endY < y {
+			y, endY = endY, y
 		}
 
-		y = y0
-		for y <= y1 {
-			plot(screen, x, y)
-			y++
+		for y <= endY {
+			plotFixed(screen, x, y, color)
+			y += step
 		}
 
 		return
 	}
 
-	slope := A / (-B)
-	var d float64
+	slope := float64(A) / float64(-B)
+	var d int64
 
 	if slope >= 0 && slope <= 1 { // octant 1
 		d = 2*A + B
-		for x <= x1 && y <= y1 {
-			plot(screen, x, y)
+		for x <= endX && y <= endY {
+			plotFixed(screen, x, y, color)
 			if d > 0 {
-				y++
+				y += step
 				d += 2 * B
 			}
-			x++
+			x += step
 			d += 2 * A
 		}
 	}
 
 	if slope > 1 { // octant 2
 		d = A + 2*B
-		for x <= x1 && y <= y1 {
-			plot(screen, x, y)
+		for x <= endX && y <= endY {
+			plotFixed(screen, x, y, color)
 			if d < 0 {
-				x++
+				x += step
 				d += 2 * A
 			}
-			y++
+			y += step
 			d += 2 * B
 		}
 	}
 
 	if slope < 0 && slope >= -1 { // octant 8
 		d = 2*A - B
-		for x <= x1 && y >= y1 {
-			plot(screen, x, y)
+		for x <= endX && y >= endY {
+			plotFixed(screen, x, y, color)
 			if d < 0 {
-				y--
+				y -= step
 				d -= 2 * B
 			}
-			x++
+			x += step
 			d += 2 * A
 		}
 	}
 
 	if slope < -1 { // octant 7
 		d = A - 2*B
-		for x <= x1 && y >= y1 {
-			plot(screen, x, y)
+		for x <= endX && y >= endY {
+			plotFixed(screen, x, y, color)
 			if d > 0 {
-				x++
+				x += step
 				d += 2 * A
 			}
-			y--
+			y -= step
 			d -= 2 * B
 		}
 	}
@@ -253,11 +628,38 @@ func SetColor(color string) {
 	}
 }
 
-// plot draws a point (x, y) onto a screen with the default draw color.
-func plot(screen [][][]int, x, y float64) {
+// plotColor draws a point (x, y) onto a screen with the given color.
+func plotColor(screen [][][]int, x, y float64, color []int) {
 	newX, newY := float64ToInt(x), display.YRES-float64ToInt(y)-1
 	if newX >= 0 && newX < display.XRES && newY >= 0 && newY < display.YRES {
-		screen[newY][newX] = DefaultDrawColor[:]
+		screen[newY][newX] = color[:]
+	}
+}
+
+// plotFixed draws a point (x, y), given as 26.6 fixed-point, onto a screen
+// with the given color.
+func plotFixed(screen [][][]int, x, y int64, color []int) {
+	plotColor(screen, float64(roundFixed(x)), float64(roundFixed(y)), color)
+}
+
+// roundFixed converts a 26.6 fixed-point value to its nearest pixel,
+// rounding exact halfway cases to even so a line rounds identically
+// regardless of which endpoint it's drawn from.
+func roundFixed(f int64) int {
+	const half = int64(fixOne) / 2
+	whole := f >> fixShift
+	frac := f & (int64(fixOne) - 1)
+
+	switch {
+	case frac < half:
+		return int(whole)
+	case frac > half:
+		return int(whole) + 1
+	default:
+		if whole%2 == 0 {
+			return int(whole)
+		}
+		return int(whole) + 1
 	}
 }
 
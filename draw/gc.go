@@ -0,0 +1,317 @@
+package draw
+
+import (
+	"math"
+
+	"github.com/jkao1/yet-another-3d-thing/matrix"
+)
+
+// point is a single (x, y) vertex of a GraphicContext path, already run
+// through the context's transform.
+type point struct {
+	x, y float64
+}
+
+// GraphicContext is a stateful drawing context: it owns a screen, the
+// current draw color, the current transform, and pen settings (line width,
+// dash pattern), and builds up a path via MoveTo/LineTo/CurveTo that Stroke
+// and Fill render. DrawLine and DrawPolygons are one-off free-function
+// equivalents: each builds a default GraphicContext for a single
+// MoveTo/LineTo.../Stroke-or-Fill call, so old scripts that call them
+// directly keep working unchanged.
+type GraphicContext struct {
+	Screen    [][][]int
+	Color     []int
+	Transform [][]float64
+	LineWidth float64
+	Dash      []float64
+	DashPhase float64
+
+	path           [][]point
+	transformStack [][][]float64
+}
+
+// NewGraphicContext creates a GraphicContext that draws onto screen with
+// DefaultDrawColor, an identity transform, and a 1px solid line.
+func NewGraphicContext(screen [][][]int) *GraphicContext {
+	transform := matrix.NewMatrix()
+	matrix.MakeIdentity(transform)
+
+	return &GraphicContext{
+		Screen:    screen,
+		Color:     append([]int{}, DefaultDrawColor...),
+		Transform: transform,
+		LineWidth: 1,
+	}
+}
+
+// transformPoint applies gc.Transform to a user-space point.
+func (gc *GraphicContext) transformPoint(x, y float64) (float64, float64) {
+	col := matrix.NewMatrix(4, 1)
+	col[0][0], col[1][0], col[2][0], col[3][0] = x, y, 0, 1
+	transform := gc.Transform
+	matrix.MultiplyMatrices(&transform, &col)
+	return col[0][0], col[1][0]
+}
+
+// MoveTo starts a new subpath at (x, y).
+func (gc *GraphicContext) MoveTo(x, y float64) {
+	tx, ty := gc.transformPoint(x, y)
+	gc.path = append(gc.path, []point{{tx, ty}})
+}
+
+// LineTo appends a straight segment from the current point to (x, y). If
+// there's no current subpath, it starts one at (x, y) instead.
+func (gc *GraphicContext) LineTo(x, y float64) {
+	if len(gc.path) == 0 {
+		gc.MoveTo(x, y)
+		return
+	}
+
+	tx, ty := gc.transformPoint(x, y)
+	subpath := &gc.path[len(gc.path)-1]
+	*subpath = append(*subpath, point{tx, ty})
+}
+
+// CurveTo appends a cubic Bezier from the current point through control
+// points (x1, y1) and (x2, y2) to (x3, y3), flattened via the same adaptive
+// de Casteljau subdivision AddCurveAdaptive uses. If there's no current
+// subpath, it starts one at (x1, y1).
+func (gc *GraphicContext) CurveTo(x1, y1, x2, y2, x3, y3 float64) {
+	if len(gc.path) == 0 {
+		gc.MoveTo(x1, y1)
+	}
+
+	tx1, ty1 := gc.transformPoint(x1, y1)
+	tx2, ty2 := gc.transformPoint(x2, y2)
+	tx3, ty3 := gc.transformPoint(x3, y3)
+
+	subpath := &gc.path[len(gc.path)-1]
+	last := (*subpath)[len(*subpath)-1]
+	flattenCubic(subpath, last.x, last.y, tx1, ty1, tx2, ty2, tx3, ty3, DefaultCurveTolerance, 0)
+}
+
+// flattenCubic recursively splits the cubic Bezier curve (x0,y0)-(x3,y3) via
+// de Casteljau's algorithm, appending the endpoint of each flat-enough leaf
+// to *pts. It shares its flatness test and midpoint math with
+// subdivideBezier, the edge-matrix equivalent used by AddCurveAdaptive.
+func flattenCubic(pts *[]point, x0, y0, x1, y1, x2, y2, x3, y3, tolerance float64, depth int) {
+	if depth >= maxCurveRecursionDepth || isFlat(x0, y0, x1, y1, x2, y2, x3, y3, tolerance) {
+		*pts = append(*pts, point{x3, y3})
+		return
+	}
+
+	x01, y01 := midpoint(x0, y0, x1, y1)
+	x12, y12 := midpoint(x1, y1, x2, y2)
+	x23, y23 := midpoint(x2, y2, x3, y3)
+	x012, y012 := midpoint(x01, y01, x12, y12)
+	x123, y123 := midpoint(x12, y12, x23, y23)
+	x0123, y0123 := midpoint(x012, y012, x123, y123)
+
+	flattenCubic(pts, x0, y0, x01, y01, x012, y012, x0123, y0123, tolerance, depth+1)
+	flattenCubic(pts, x0123, y0123, x123, y123, x23, y23, x3, y3, tolerance, depth+1)
+}
+
+// ClosePath appends a segment from the current point back to the start of
+// the current subpath.
+func (gc *GraphicContext) ClosePath() {
+	if len(gc.path) == 0 {
+		return
+	}
+
+	subpath := &gc.path[len(gc.path)-1]
+	if len(*subpath) == 0 {
+		return
+	}
+
+	*subpath = append(*subpath, (*subpath)[0])
+}
+
+// SetLineWidth sets the pen width, in pixels, used by Stroke.
+func (gc *GraphicContext) SetLineWidth(width float64) {
+	gc.LineWidth = width
+}
+
+// SetDash sets the dash pattern used by Stroke: alternating on/off lengths,
+// in pixels, starting phase pixels into the pattern. An empty pattern draws
+// a solid line.
+func (gc *GraphicContext) SetDash(pattern []float64, phase float64) {
+	gc.Dash = pattern
+	gc.DashPhase = phase
+}
+
+// Save pushes a copy of the current transform onto the transform stack.
+func (gc *GraphicContext) Save() {
+	saved := make([][]float64, len(gc.Transform))
+	for i, row := range gc.Transform {
+		saved[i] = append([]float64{}, row...)
+	}
+	gc.transformStack = append(gc.transformStack, saved)
+}
+
+// Restore pops the transform stack into the current transform. It's a no-op
+// if the stack is empty.
+func (gc *GraphicContext) Restore() {
+	if len(gc.transformStack) == 0 {
+		return
+	}
+
+	last := len(gc.transformStack) - 1
+	gc.Transform = gc.transformStack[last]
+	gc.transformStack = gc.transformStack[:last]
+}
+
+// Translate composes a translation by (tx, ty, tz) onto the current
+// transform.
+func (gc *GraphicContext) Translate(tx, ty, tz float64) {
+	step := matrix.MakeTranslationMatrix(tx, ty, tz)
+	matrix.MultiplyMatrices(&step, &gc.Transform)
+}
+
+// Scale composes a scale by (sx, sy, sz) onto the current transform.
+func (gc *GraphicContext) Scale(sx, sy, sz float64) {
+	step := matrix.MakeDilationMatrix(sx, sy, sz)
+	matrix.MultiplyMatrices(&step, &gc.Transform)
+}
+
+// Rotate composes a rotation of theta degrees about axis ("x", "y" or "z")
+// onto the current transform.
+func (gc *GraphicContext) Rotate(axis string, theta float64) {
+	var step [][]float64
+	switch axis {
+	case "x":
+		step = matrix.MakeRotX(theta)
+	case "y":
+		step = matrix.MakeRotY(theta)
+	case "z":
+		step = matrix.MakeRotZ(theta)
+	}
+	matrix.MultiplyMatrices(&step, &gc.Transform)
+}
+
+// Fill scanline-fills every subpath with 3 or more points as a polygon,
+// using gc.Color.
+func (gc *GraphicContext) Fill() {
+	for _, subpath := range gc.path {
+		if len(subpath) < 3 {
+			continue
+		}
+
+		verts := make([][]float64, len(subpath))
+		for i, p := range subpath {
+			verts[i] = []float64{p.x, p.y, 0, 1}
+		}
+		fillPolygonColor(gc.Screen, verts, gc.Color)
+	}
+}
+
+// Stroke draws every subpath's segments with gc.Color, gc.LineWidth and
+// gc.Dash.
+func (gc *GraphicContext) Stroke() {
+	for _, subpath := range gc.path {
+		gc.strokeSubpath(subpath)
+	}
+}
+
+func (gc *GraphicContext) strokeSubpath(pts []point) {
+	if len(pts) < 2 {
+		return
+	}
+
+	dashIndex, dashRemaining, penDown := 0, math.Inf(1), true
+	if len(gc.Dash) > 0 {
+		dashIndex, dashRemaining, penDown = dashStateAtPhase(gc.Dash, gc.DashPhase)
+	}
+
+	for i := 0; i+1 < len(pts); i++ {
+		gc.strokeSegment(pts[i], pts[i+1], &dashIndex, &dashRemaining, &penDown)
+	}
+}
+
+// dashStateAtPhase walks dash (alternating on/off lengths) phase pixels in,
+// wrapping around the pattern's total length, and returns which dash index
+// that lands in, how much of that dash segment is left, and whether the pen
+// is down (on) at that point.
+func dashStateAtPhase(dash []float64, phase float64) (index int, remaining float64, on bool) {
+	total := 0.0
+	for _, d := range dash {
+		total += d
+	}
+	if total <= 0 {
+		return 0, math.Inf(1), true
+	}
+
+	phase = math.Mod(phase, total)
+	if phase < 0 {
+		phase += total
+	}
+
+	on = true
+	for phase >= dash[index] {
+		phase -= dash[index]
+		index = (index + 1) % len(dash)
+		on = !on
+	}
+	remaining = dash[index] - phase
+	return
+}
+
+// strokeSegment draws the segment p0-p1, splitting it at dash boundaries and
+// only drawing the "on" pieces when gc.Dash is set. dashIndex, dashRemaining
+// and penDown track progress through the dash pattern across the whole
+// subpath.
+func (gc *GraphicContext) strokeSegment(p0, p1 point, dashIndex *int, dashRemaining *float64, penDown *bool) {
+	if len(gc.Dash) == 0 {
+		gc.drawSegment(p0, p1)
+		return
+	}
+
+	length := math.Hypot(p1.x-p0.x, p1.y-p0.y)
+	if length == 0 {
+		return
+	}
+	dx, dy := (p1.x-p0.x)/length, (p1.y-p0.y)/length
+
+	cur := p0
+	traveled := 0.0
+	for traveled < length {
+		step := math.Min(*dashRemaining, length-traveled)
+		next := point{cur.x + dx*step, cur.y + dy*step}
+
+		if *penDown {
+			gc.drawSegment(cur, next)
+		}
+
+		traveled += step
+		*dashRemaining -= step
+		cur = next
+
+		if *dashRemaining <= 1e-9 {
+			*dashIndex = (*dashIndex + 1) % len(gc.Dash)
+			*dashRemaining = gc.Dash[*dashIndex]
+			*penDown = !*penDown
+		}
+	}
+}
+
+// drawSegment draws a single solid chord from p0 to p1 at gc.LineWidth,
+// approximating width by drawing parallel 1px lines offset along the
+// segment's normal.
+func (gc *GraphicContext) drawSegment(p0, p1 point) {
+	if gc.LineWidth <= 1 {
+		rasterizeLine(gc.Screen, p0.x, p0.y, p1.x, p1.y, gc.Color)
+		return
+	}
+
+	length := math.Hypot(p1.x-p0.x, p1.y-p0.y)
+	if length == 0 {
+		rasterizeLine(gc.Screen, p0.x, p0.y, p1.x, p1.y, gc.Color)
+		return
+	}
+
+	nx, ny := -(p1.y-p0.y)/length, (p1.x-p0.x)/length
+	half := (gc.LineWidth - 1) / 2
+	for offset := -half; offset <= half; offset++ {
+		rasterizeLine(gc.Screen, p0.x+nx*offset, p0.y+ny*offset, p1.x+nx*offset, p1.y+ny*offset, gc.Color)
+	}
+}
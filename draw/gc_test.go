@@ -0,0 +1,137 @@
+package draw
+
+import (
+	"testing"
+
+	"github.com/jkao1/yet-another-3d-thing/display"
+)
+
+// TestStrokeDrawsLine checks that Stroke rasterizes a simple path's segments
+// onto the screen in gc.Color.
+func TestStrokeDrawsLine(t *testing.T) {
+	screen := display.NewScreen()
+	gc := NewGraphicContext(screen)
+	gc.Color = []int{255, 255, 255}
+	gc.MoveTo(10, 10)
+	gc.LineTo(20, 10)
+	gc.Stroke()
+
+	row := display.YRES - 10 - 1
+	for x := 10; x <= 20; x++ {
+		if got := screen[row][x][0]; got != 255 {
+			t.Errorf("pixel (%d, 10): got color %d, want 255", x, got)
+		}
+	}
+}
+
+// TestFillFillsTriangle checks that Fill scanline-fills a closed subpath of
+// 3 or more points, and leaves pixels outside it untouched.
+func TestFillFillsTriangle(t *testing.T) {
+	screen := display.NewScreen()
+	gc := NewGraphicContext(screen)
+	gc.Color = []int{255, 255, 255}
+	gc.MoveTo(10, 10)
+	gc.LineTo(50, 10)
+	gc.LineTo(30, 40)
+	gc.ClosePath()
+	gc.Fill()
+
+	row := display.YRES - 15 - 1
+	if got := screen[row][30][0]; got != 255 {
+		t.Errorf("pixel (30, 15) inside the triangle: got color %d, want 255", got)
+	}
+
+	outsideRow := display.YRES - 10 - 1
+	if got := screen[outsideRow][0][0]; got != 0 {
+		t.Errorf("pixel (0, 10) outside the triangle: got color %d, want 0", got)
+	}
+}
+
+// TestSaveRestore checks that Save/Restore push and pop the transform as a
+// stack, rather than just undoing the single most recent step.
+func TestSaveRestore(t *testing.T) {
+	screen := display.NewScreen()
+	gc := NewGraphicContext(screen)
+
+	gc.Translate(5, 0, 0)
+	gc.Save()
+	gc.Translate(100, 0, 0)
+	gc.Translate(100, 0, 0)
+	gc.Restore()
+
+	x, y := gc.transformPoint(0, 0)
+	if x != 5 || y != 0 {
+		t.Errorf("transformPoint(0, 0) after restore = (%v, %v), want (5, 0)", x, y)
+	}
+}
+
+// TestRestoreEmptyStackIsNoOp checks that Restore with nothing saved leaves
+// the current transform alone instead of panicking or clearing it.
+func TestRestoreEmptyStackIsNoOp(t *testing.T) {
+	screen := display.NewScreen()
+	gc := NewGraphicContext(screen)
+	gc.Translate(5, 0, 0)
+	gc.Restore()
+
+	x, y := gc.transformPoint(0, 0)
+	if x != 5 || y != 0 {
+		t.Errorf("transformPoint(0, 0) after no-op restore = (%v, %v), want (5, 0)", x, y)
+	}
+}
+
+// TestDashStateAtPhase checks dashStateAtPhase's phase-to-dash-index math,
+// including wrapping past the end of the pattern and negative phases, since
+// an off-by-one here would shift every dash boundary in a stroked line.
+func TestDashStateAtPhase(t *testing.T) {
+	dash := []float64{4, 2} // total period 6: 4 on, 2 off
+
+	tests := []struct {
+		phase         float64
+		wantIndex     int
+		wantRemaining float64
+		wantOn        bool
+	}{
+		{phase: 0, wantIndex: 0, wantRemaining: 4, wantOn: true},
+		{phase: 1, wantIndex: 0, wantRemaining: 3, wantOn: true},
+		{phase: 5, wantIndex: 1, wantRemaining: 1, wantOn: false},
+		{phase: 7, wantIndex: 0, wantRemaining: 3, wantOn: true},   // wraps to phase 1
+		{phase: -2, wantIndex: 1, wantRemaining: 2, wantOn: false}, // wraps to phase 4
+	}
+
+	for _, tt := range tests {
+		index, remaining, on := dashStateAtPhase(dash, tt.phase)
+		if index != tt.wantIndex || remaining != tt.wantRemaining || on != tt.wantOn {
+			t.Errorf("dashStateAtPhase(%v, %v) = (%v, %v, %v), want (%v, %v, %v)",
+				dash, tt.phase, index, remaining, on, tt.wantIndex, tt.wantRemaining, tt.wantOn)
+		}
+	}
+}
+
+// TestStrokeDash checks that Stroke only draws the "on" pieces of a dashed
+// line, by sampling pixels well inside an on-segment and well inside an
+// off-segment rather than asserting on the exact boundary pixel (which can
+// shift by a pixel depending on rounding).
+func TestStrokeDash(t *testing.T) {
+	screen := display.NewScreen()
+	gc := NewGraphicContext(screen)
+	gc.Color = []int{255, 255, 255}
+	gc.SetDash([]float64{4, 2}, 0)
+	gc.MoveTo(0, 10)
+	gc.LineTo(20, 10)
+	gc.Stroke()
+
+	row := display.YRES - 10 - 1
+	onPixels := []int{1, 7, 13}
+	offPixels := []int{5, 11, 17}
+
+	for _, x := range onPixels {
+		if got := screen[row][x][0]; got != 255 {
+			t.Errorf("pixel (%d, 10), expected on: got color %d, want 255", x, got)
+		}
+	}
+	for _, x := range offPixels {
+		if got := screen[row][x][0]; got != 0 {
+			t.Errorf("pixel (%d, 10), expected off: got color %d, want 0", x, got)
+		}
+	}
+}
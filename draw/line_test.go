@@ -0,0 +1,68 @@
+package draw
+
+import (
+	"testing"
+
+	"github.com/jkao1/yet-another-3d-thing/display"
+)
+
+// screensEqual reports whether two screens have identical pixel data.
+func screensEqual(a, b [][][]int) bool {
+	for y := range a {
+		for x := range a[y] {
+			if len(a[y][x]) != len(b[y][x]) {
+				return false
+			}
+			for i := range a[y][x] {
+				if a[y][x][i] != b[y][x][i] {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// TestDrawLineOrderIndependent checks that DrawLine(a, b) and DrawLine(b, a)
+// always rasterize to the same pixels, since drawLineFixed sorts its
+// endpoints before stepping through them.
+func TestDrawLineOrderIndependent(t *testing.T) {
+	cases := []struct{ x0, y0, x1, y1 float64 }{
+		{10, 10, 400, 300},
+		{0, 0, 0, 200},
+		{0, 0, 200, 0},
+		{50, 450, 450, 50},
+		{100, 100, 100.5, 300.7},
+		{300, 300, 300, 300},
+	}
+
+	for _, c := range cases {
+		forward := display.NewScreen()
+		backward := display.NewScreen()
+		DrawLine(forward, c.x0, c.y0, c.x1, c.y1)
+		DrawLine(backward, c.x1, c.y1, c.x0, c.y0)
+
+		if !screensEqual(forward, backward) {
+			t.Errorf("DrawLine(%v,%v,%v,%v) depends on endpoint order", c.x0, c.y0, c.x1, c.y1)
+		}
+	}
+}
+
+// FuzzDrawLineOrderIndependent fuzzes endpoint coordinates, verifying
+// DrawLine(a, b) always rasterizes identically to DrawLine(b, a).
+func FuzzDrawLineOrderIndependent(f *testing.F) {
+	f.Add(10.0, 10.0, 400.0, 300.0)
+	f.Add(0.0, 0.0, 0.0, 0.0)
+	f.Add(-5.0, -5.0, 600.0, 600.0)
+
+	f.Fuzz(func(t *testing.T, x0, y0, x1, y1 float64) {
+		forward := display.NewScreen()
+		backward := display.NewScreen()
+		DrawLine(forward, x0, y0, x1, y1)
+		DrawLine(backward, x1, y1, x0, y0)
+
+		if !screensEqual(forward, backward) {
+			t.Errorf("DrawLine(%v,%v,%v,%v) depends on endpoint order", x0, y0, x1, y1)
+		}
+	})
+}
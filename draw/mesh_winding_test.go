@@ -0,0 +1,84 @@
+package draw
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jkao1/yet-another-3d-thing/matrix"
+)
+
+// assertMeshWindsOutward checks that every triangle in polygons has a normal
+// that points away from outward(centroid), where outward computes the
+// expected outward direction for that triangle's centroid. This is a
+// stronger check than isFrontFacing's normal.z > 0 test: it catches inverted
+// winding regardless of the mesh's orientation on screen.
+func assertMeshWindsOutward(t *testing.T, name string, polygons [][]float64, outward func(centroid []float64) []float64) {
+	t.Helper()
+
+	triangles := 0
+	for i := 0; i < len(polygons[0])-2; i += 3 {
+		p0 := matrix.ExtractColumn(polygons, i)
+		p1 := matrix.ExtractColumn(polygons, i+1)
+		p2 := matrix.ExtractColumn(polygons, i+2)
+
+		normal := matrix.CrossProduct(matrix.Subtract(p1, p0), matrix.Subtract(p2, p0))
+		centroid := []float64{
+			(p0[0] + p1[0] + p2[0]) / 3,
+			(p0[1] + p1[1] + p2[1]) / 3,
+			(p0[2] + p1[2] + p2[2]) / 3,
+		}
+
+		out := outward(centroid)
+		dot := normal[0]*out[0] + normal[1]*out[1] + normal[2]*out[2]
+		if dot <= 0 {
+			t.Errorf("%s: triangle %d winds inward (normal . outward = %v)", name, i/3, dot)
+		}
+		triangles++
+	}
+
+	if triangles == 0 {
+		t.Fatalf("%s: no triangles to check", name)
+	}
+}
+
+func TestAddSpherePolygonsWindOutward(t *testing.T) {
+	polygons := make([][]float64, 4)
+	colors := make([][]int, 0)
+	cx, cy, cz, r := 250.0, 250.0, 0.0, 100.0
+	AddSpherePolygons(polygons, &colors, []int{0, 0, 0}, cx, cy, cz, r)
+
+	assertMeshWindsOutward(t, "sphere", polygons, func(centroid []float64) []float64 {
+		return []float64{centroid[0] - cx, centroid[1] - cy, centroid[2] - cz}
+	})
+}
+
+func TestAddBoxPolygonsWindOutward(t *testing.T) {
+	polygons := make([][]float64, 4)
+	colors := make([][]int, 0)
+	x, y, z, width, height, depth := 100.0, 300.0, 0.0, 50.0, 50.0, 50.0
+	AddBoxPolygons(polygons, &colors, []int{0, 0, 0}, x, y, z, width, height, depth)
+
+	cx, cy, cz := x+width/2, y-height/2, z-depth/2
+
+	assertMeshWindsOutward(t, "box", polygons, func(centroid []float64) []float64 {
+		return []float64{centroid[0] - cx, centroid[1] - cy, centroid[2] - cz}
+	})
+}
+
+func TestAddTorusPolygonsWindOutward(t *testing.T) {
+	polygons := make([][]float64, 4)
+	colors := make([][]int, 0)
+	cx, cy, cz := 250.0, 250.0, 0.0
+	tubeRadius, ringRadius := 50.0, 150.0
+	AddTorusPolygons(polygons, &colors, []int{0, 0, 0}, cx, cy, cz, tubeRadius, ringRadius)
+
+	// A torus has no single center: the outward direction at a surface point
+	// is relative to the tube's local center, which sits ringRadius out from
+	// (cx, cy, cz) along that point's (x, z) direction.
+	assertMeshWindsOutward(t, "torus", polygons, func(centroid []float64) []float64 {
+		dx, dz := centroid[0]-cx, centroid[2]-cz
+		length := math.Hypot(dx, dz)
+		tubeCenter := []float64{cx + ringRadius*dx/length, cy, cz + ringRadius*dz/length}
+		return []float64{centroid[0] - tubeCenter[0], centroid[1] - tubeCenter[1], centroid[2] - tubeCenter[2]}
+	})
+}
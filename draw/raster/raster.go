@@ -0,0 +1,138 @@
+// Package raster implements an antialiased line rasterizer, used as an
+// alternative to draw's aliased Bresenham DrawLine.
+package raster
+
+import (
+	"math"
+
+	"github.com/jkao1/yet-another-3d-thing/display"
+)
+
+// fixedShift is the number of fractional bits in the 24.8 fixed-point
+// coordinates used while walking a line.
+const fixedShift = 8
+const fixedOne = 1 << fixedShift
+
+// coverageTableSize is the number of entries in the gamma-corrected coverage
+// table, indexed by the sub-pixel distance (0-255) of a scanline from a
+// pixel's center.
+const coverageTableSize = 256
+
+// coverageGamma gamma-corrects the linear sub-pixel coverage so antialiased
+// edges don't look washed out against the screen's (non-linear) RGB values.
+const coverageGamma = 1 / 2.2
+
+var coverageTable [coverageTableSize]uint8
+
+func init() {
+	for i := 0; i < coverageTableSize; i++ {
+		linear := float64(i) / float64(coverageTableSize-1)
+		coverageTable[i] = uint8(math.Round(math.Pow(linear, coverageGamma) * 255))
+	}
+}
+
+// toFixed converts a float64 to 24.8 fixed-point.
+func toFixed(f float64) int64 {
+	return int64(math.Round(f * fixedOne))
+}
+
+// ipart returns the integer (pixel) part of a fixed-point coordinate.
+func ipart(f int64) int64 {
+	return f >> fixedShift
+}
+
+// fpart returns the fractional part of a fixed-point coordinate, as a
+// sub-pixel distance in [0, 255] suitable for indexing coverageTable.
+func fpart(f int64) uint8 {
+	return uint8((f & (fixedOne - 1)) * (coverageTableSize - 1) / fixedOne)
+}
+
+// DrawLine draws an antialiased line from (x0, y0) to (x1, y1) onto screen,
+// blending fg into the existing framebuffer value at each pixel using a
+// coverage-table-derived alpha.
+func DrawLine(screen [][][]int, x0, y0, x1, y1 float64, fg []int) {
+	steep := math.Abs(y1-y0) > math.Abs(x1-x0)
+	if steep {
+		x0, y0 = y0, x0
+		x1, y1 = y1, x1
+	}
+	if x0 > x1 {
+		x0, x1 = x1, x0
+		y0, y1 = y1, y0
+	}
+
+	dx := x1 - x0
+	dy := y1 - y0
+	gradient := 1.0
+	if dx != 0 {
+		gradient = dy / dx
+	}
+	gradientFixed := toFixed(gradient)
+
+	plotAt := func(x, y int64, coverage uint8) {
+		if steep {
+			blend(screen, int(y), int(x), coverage, fg)
+		} else {
+			blend(screen, int(x), int(y), coverage, fg)
+		}
+	}
+
+	// First endpoint.
+	xEnd := math.Round(x0)
+	yEnd := y0 + gradient*(xEnd-x0)
+	xGap := 1 - fracPixel(x0+0.5)
+	xpxl1 := int64(xEnd)
+	yIntery := toFixed(yEnd)
+	plotAt(xpxl1, ipart(yIntery), scaleCoverage(255-fpart(yIntery), xGap))
+	plotAt(xpxl1, ipart(yIntery)+1, scaleCoverage(fpart(yIntery), xGap))
+
+	intery := yIntery + gradientFixed
+
+	// Second endpoint.
+	xEnd = math.Round(x1)
+	yEnd = y1 + gradient*(xEnd-x1)
+	xGap = fracPixel(x1 + 0.5)
+	xpxl2 := int64(xEnd)
+	yEndFixed := toFixed(yEnd)
+	plotAt(xpxl2, ipart(yEndFixed), scaleCoverage(255-fpart(yEndFixed), xGap))
+	plotAt(xpxl2, ipart(yEndFixed)+1, scaleCoverage(fpart(yEndFixed), xGap))
+
+	for x := xpxl1 + 1; x < xpxl2; x++ {
+		plotAt(x, ipart(intery), 255-fpart(intery))
+		plotAt(x, ipart(intery)+1, fpart(intery))
+		intery += gradientFixed
+	}
+}
+
+// fracPixel returns the fractional part of f.
+func fracPixel(f float64) float64 {
+	return f - math.Floor(f)
+}
+
+// scaleCoverage scales a 0-255 coverage value by a 0-1 factor.
+func scaleCoverage(coverage uint8, factor float64) uint8 {
+	return uint8(math.Round(float64(coverage) * factor))
+}
+
+// blend writes (x, y) onto screen, mixing fg into the existing pixel value
+// using coverageTable[coverage] as the alpha: out = alpha*fg + (1-alpha)*bg.
+// y is flipped the same way plotColor/plotFixed in draw.go flip it, so
+// antialiased and aliased lines land on the same rows.
+func blend(screen [][][]int, x, y int, coverage uint8, fg []int) {
+	row := display.YRES - y - 1
+	if x < 0 || x >= display.XRES || row < 0 || row >= display.YRES {
+		return
+	}
+
+	alpha := float64(coverageTable[coverage]) / 255
+	bg := screen[row][x]
+	screen[row][x] = []int{
+		mix(fg[0], bg[0], alpha),
+		mix(fg[1], bg[1], alpha),
+		mix(fg[2], bg[2], alpha),
+	}
+}
+
+func mix(fg, bg int, alpha float64) int {
+	return int(math.Round(alpha*float64(fg) + (1-alpha)*float64(bg)))
+}
@@ -0,0 +1,109 @@
+package raster
+
+import (
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jkao1/yet-another-3d-thing/display"
+)
+
+// TestDrawLineGoldenDiagonal draws a 45-degree diagonal and checks it against
+// Wu's algorithm's expected coverage pattern: the two endpoint pixels split
+// coverage with their neighbor (indexed through coverageTable as "half"),
+// while the interior pixels get full coverage.
+func TestDrawLineGoldenDiagonal(t *testing.T) {
+	screen := display.NewScreen()
+	fg := []int{255, 255, 255}
+	DrawLine(screen, 0, 0, 3, 3, fg)
+
+	full := int(coverageTable[255])
+	half := int(coverageTable[128])
+
+	want := map[[2]int]int{
+		{0, 0}: half,
+		{1, 1}: full,
+		{2, 2}: full,
+		{3, 3}: half,
+	}
+
+	for xy, expected := range want {
+		x, y := xy[0], xy[1]
+		row := display.YRES - y - 1
+		if got := screen[row][x][0]; got != expected {
+			t.Errorf("pixel (%d, %d): got %d, want %d", x, y, got, expected)
+		}
+	}
+}
+
+// maxFanPixelDiff is the largest per-channel difference TestDrawLineFanMatchesReference
+// tolerates against its reference image, to absorb any last-bit floating-point
+// rounding slack between runs without masking a real regression.
+const maxFanPixelDiff = 2
+
+// TestDrawLineFanMatchesReference renders a fan of 36 lines (one spoke every
+// 10 degrees) radiating from a shared center point and checks the result
+// against testdata/fan36_reference.png pixel-for-pixel, asserting the max
+// per-pixel diff stays within maxFanPixelDiff.
+func TestDrawLineFanMatchesReference(t *testing.T) {
+	screen := display.NewScreen()
+	fg := []int{255, 255, 255}
+	cx, cy, radius := 250.0, 250.0, 200.0
+
+	for deg := 0; deg < 360; deg += 10 {
+		theta := float64(deg) * math.Pi / 180
+		x1 := cx + radius*math.Cos(theta)
+		y1 := cy + radius*math.Sin(theta)
+		DrawLine(screen, cx, cy, x1, y1, fg)
+	}
+
+	f, err := os.Open(filepath.Join("testdata", "fan36_reference.png"))
+	if err != nil {
+		t.Fatalf("opening reference image: %v", err)
+	}
+	defer f.Close()
+
+	reference, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("decoding reference image: %v", err)
+	}
+
+	maxDiff := 0
+	for y := 0; y < display.YRES; y++ {
+		for x := 0; x < display.XRES; x++ {
+			r, g, b, _ := reference.At(x, y).RGBA()
+			want := [3]int{int(r >> 8), int(g >> 8), int(b >> 8)}
+			got := screen[y][x]
+			for c := 0; c < 3; c++ {
+				if d := abs(got[c] - want[c]); d > maxDiff {
+					maxDiff = d
+				}
+			}
+		}
+	}
+
+	if maxDiff > maxFanPixelDiff {
+		t.Errorf("fan render differs from testdata/fan36_reference.png by up to %d (max allowed %d)", maxDiff, maxFanPixelDiff)
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// BenchmarkDrawLine measures the cost of rasterizing a single antialiased
+// line across a full-size screen.
+func BenchmarkDrawLine(b *testing.B) {
+	screen := display.NewScreen()
+	fg := []int{255, 255, 255}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		DrawLine(screen, 10, 10, float64(display.XRES-10), float64(display.YRES-10), fg)
+	}
+}
@@ -9,6 +9,7 @@ func main() {
 	screen := display.NewScreen()
 	transform := make([][]float64, 0)
 	edges := make([][]float64, 4)
+	polygons := make([][]float64, 4)
 
-	parser.ParseFile("script", transform, edges, screen)
+	parser.ParseFile("script", transform, edges, polygons, screen)
 }
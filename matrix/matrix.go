@@ -91,6 +91,41 @@ func dot(x, y []float64) float64 {
 	return output
 }
 
+// Subtract subtracts vector b from vector a, component-wise. a and b must be
+// the same length.
+func Subtract(a, b []float64) []float64 {
+	out := make([]float64, len(a))
+	for i := range a {
+		out[i] = a[i] - b[i]
+	}
+	return out
+}
+
+// CrossProduct returns the cross product of 3-vectors a and b. Only the
+// first 3 components of a and b are used, so it's safe to pass the
+// homogeneous (x, y, z, 1) columns an edge or polygon matrix stores.
+func CrossProduct(a, b []float64) []float64 {
+	return []float64{
+		a[1]*b[2] - a[2]*b[1],
+		a[2]*b[0] - a[0]*b[2],
+		a[0]*b[1] - a[1]*b[0],
+	}
+}
+
+// Normalize scales a vector to unit length.
+func Normalize(v []float64) []float64 {
+	length := math.Sqrt(v[0]*v[0] + v[1]*v[1] + v[2]*v[2])
+	if length == 0 {
+		return v
+	}
+
+	out := make([]float64, len(v))
+	for i, c := range v {
+		out[i] = c / length
+	}
+	return out
+}
+
 // NewMatrix creates a new float64 matrix. The default row and column size is 4.
 // It returns the new matrix.
 func NewMatrix(params ...int) [][]float64 {
@@ -43,11 +43,29 @@ The file follows the following format:
 	  save: draw the lines of the edge matrix to the screen save the screen to a
        file -
 	    takes 1 argument (file name)
+	  antialias: turn antialiased line drawing on or off -
+	    takes 1 argument (on or off), given on the same line
+	  mesh: turn solid polygon-mesh rendering on or off -
+	    takes 1 argument (on or off), given on the same line
+	    when on, sphere/box/torus add triangles to the polygon matrix
+	    instead of wireframe edges, in whatever color is active via
+	    "color" at the time they're added
+	  gc: open a GraphicContext block, for stateful path drawing -
+	    reads commands until "endgc":
+	      moveto, lineto: takes 2 arguments (x, y)
+	      curveto: takes 6 arguments (x1, y1, x2, y2, x3, y3)
+	      closepath, stroke, fill, save, restore: take no arguments
+	      linewidth: takes 1 argument (width)
+	      dash: takes a dash pattern (on off on off ...), optionally
+	        followed by a phase offset if given an odd number of arguments
+	      translate, scale: take 3 arguments (x, y, z)
+	      rotate: takes 2 arguments (axis, theta) axis should be x y or z
 	  quit: end parsing
 */
 func ParseFile(filename string,
 	transform [][]float64,
 	edges [][]float64,
+	polygons [][]float64,
 	screen [][][]int) {
 
 	file, err := os.Open(filename)
@@ -57,6 +75,11 @@ func ParseFile(filename string,
 
 	defer file.Close()
 
+	// polygonColors[i] is the color of the i-th triangle in polygons, so
+	// sphere/box/torus can each be drawn in whatever color was active when
+	// they were added, rather than all reading the live draw.DefaultDrawColor.
+	polygonColors := make([][]int, 0)
+
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -67,18 +90,26 @@ func ParseFile(filename string,
 			continue
 		} else if line == "display" {
 			display.ClearScreen(screen)
+			draw.DrawPolygons(polygons, polygonColors, screen)
 			draw.DrawLines(edges, screen)
 			display.DisplayScreen(screen)
 			continue
 		} else if line == "clear" {
 			edges = make([][]float64, 4)
+			polygons = make([][]float64, 4)
+			polygonColors = make([][]int, 0)
 			continue
 		} else if line == "apply" {
 			matrix.MultiplyMatrices(&transform, &edges)
+			matrix.MultiplyMatrices(&transform, &polygons)
 			continue
 		} else if line == "quit" {
 			return
+		} else if line == "gc" {
+			runGCBlock(scanner, screen)
+			continue
 		} else if line == "draw" {
+			draw.DrawPolygons(polygons, polygonColors, screen)
 			draw.DrawLines(edges, screen)
 			continue
 		} else if line == "show" {
@@ -87,6 +118,12 @@ func ParseFile(filename string,
 		}	else if strings.Contains(line, "color") {
 			draw.SetColor(strings.Fields(line)[1])
 			continue
+		} else if strings.Contains(line, "antialias") {
+			draw.SetAntialias(strings.Fields(line)[1] == "on")
+			continue
+		} else if strings.Contains(line, "mesh") {
+			draw.SetMesh(strings.Fields(line)[1] == "on")
+			continue
 		}
 
 		if len(line) == 0 || line[0] == '#' {
@@ -105,14 +142,26 @@ func ParseFile(filename string,
 		} else if line == "circle" {
 			draw.AddCircle(edges, FloatParams(params)...)
 		} else if line == "sphere" {
-			draw.AddSphere(edges, FloatParams(params)...)
+			if draw.Mesh() {
+				draw.AddSpherePolygons(polygons, &polygonColors, draw.DefaultDrawColor, FloatParams(params)...)
+			} else {
+				draw.AddSphere(edges, FloatParams(params)...)
+			}
 		} else if line == "box" {
-			draw.AddBox(edges, FloatParams(params)...)
+			if draw.Mesh() {
+				draw.AddBoxPolygons(polygons, &polygonColors, draw.DefaultDrawColor, FloatParams(params)...)
+			} else {
+				draw.AddBox(edges, FloatParams(params)...)
+			}
 		} else if line == "torus" {
-			draw.AddTorus(edges, FloatParams(params)...)
+			if draw.Mesh() {
+				draw.AddTorusPolygons(polygons, &polygonColors, draw.DefaultDrawColor, FloatParams(params)...)
+			} else {
+				draw.AddTorus(edges, FloatParams(params)...)
+			}
 		} else if line == "hermite" || line == "bezier" {
 			p := FloatParams(params)
-			draw.AddCurve(edges, p[0], p[1], p[2], p[3], p[4], p[5], p[6], p[7], 0.001, line)
+			draw.AddCurveAdaptive(edges, p[0], p[1], p[2], p[3], p[4], p[5], p[6], p[7], draw.DefaultCurveTolerance, line)
 		}	else {
 			var stepTransform [][]float64
 
@@ -150,6 +199,83 @@ func ParseFile(filename string,
 	}
 }
 
+// runGCBlock reads commands from scanner into a fresh GraphicContext bound
+// to screen, until it hits "endgc". See ParseFile's doc comment for the
+// block's commands.
+func runGCBlock(scanner *bufio.Scanner, screen [][][]int) {
+	gc := draw.NewGraphicContext(screen)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if line == "endgc" {
+			return
+		}
+
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+
+		switch line {
+		case "closepath":
+			gc.ClosePath()
+			continue
+		case "stroke":
+			gc.Stroke()
+			continue
+		case "fill":
+			gc.Fill()
+			continue
+		case "save":
+			gc.Save()
+			continue
+		case "restore":
+			gc.Restore()
+			continue
+		}
+
+		scanner.Scan()
+		params := scanner.Text()
+
+		switch line {
+		case "moveto":
+			p := FloatParams(params)
+			gc.MoveTo(p[0], p[1])
+		case "lineto":
+			p := FloatParams(params)
+			gc.LineTo(p[0], p[1])
+		case "curveto":
+			p := FloatParams(params)
+			gc.CurveTo(p[0], p[1], p[2], p[3], p[4], p[5])
+		case "linewidth":
+			p := FloatParams(params)
+			gc.SetLineWidth(p[0])
+		case "dash":
+			p := FloatParams(params)
+			phase := 0.0
+			pattern := p
+			if len(p)%2 == 1 {
+				phase = p[len(p)-1]
+				pattern = p[:len(p)-1]
+			}
+			gc.SetDash(pattern, phase)
+		case "translate":
+			p := FloatParams(params)
+			gc.Translate(p[0], p[1], p[2])
+		case "scale":
+			p := FloatParams(params)
+			gc.Scale(p[0], p[1], p[2])
+		case "rotate":
+			args := strings.Fields(params)
+			theta, err := strconv.ParseFloat(args[1], 64)
+			if err != nil {
+				panic(err)
+			}
+			gc.Rotate(args[0], theta)
+		}
+	}
+}
+
 func FloatParams(text string) (args []float64) {
 	args = []float64{}
 	for _, v := range strings.Fields(text) {